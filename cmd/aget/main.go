@@ -0,0 +1,64 @@
+// Command aget is a smoke-test CLI for gpiodriver/iioadc, mirroring the
+// get/set commands in gpio-util but for analog pins.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"libdb.so/periph-gpioc/gpiodriver"
+	"libdb.so/periph-gpioc/gpiodriver/iioadc"
+	"libdb.so/periph-gpioc/internal/xcli"
+)
+
+var verbose bool
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] <pin>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		flag.PrintDefaults()
+	}
+	flag.BoolVar(&verbose, "v", false, "enable verbose logging")
+	flag.Parse()
+
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	}
+
+	logger := xcli.NewColoredLogger(os.Stderr, level)
+	slog.SetDefault(logger)
+
+	os.Exit(run())
+}
+
+func run() int {
+	if flag.NArg() != 1 {
+		flag.Usage()
+		return 1
+	}
+
+	if err := gpiodriver.Register(gpiodriver.WithIIO()); err != nil {
+		slog.Error("failed to register gpiochips", "err", err)
+		return 1
+	}
+
+	pin := iioadc.ByName(flag.Arg(0))
+	if pin == nil {
+		slog.Error("invalid pin", "pin", flag.Arg(0))
+		return 1
+	}
+
+	sample, err := pin.Read()
+	if err != nil {
+		slog.Error("failed to read pin", "err", err)
+		return 1
+	}
+
+	fmt.Printf("raw=%d v=%s\n", sample.Raw, sample.V)
+	return 0
+}
@@ -0,0 +1,146 @@
+// Command periph-gpioc is the primary CLI for this repository: it registers
+// the local gpiochips and lets you dump, read, or write pins, with a
+// configurable logging pipeline (see the --log.* flags).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"libdb.so/periph-gpioc/gpiodriver"
+	"libdb.so/periph-gpioc/internal/xcli"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+)
+
+var (
+	logFormat = flag.String("log.format", "auto", "log output format: auto, text, logfmt, json, compact")
+	logLevel  = flag.String("log.level", "info", "minimum log level: debug, info, warn, error")
+	logColor  = flag.String("log.color", "auto", "colored log output: auto, always, never")
+
+	logFile           = flag.String("log.file", "", "additionally write JSON logs to this rotating file")
+	logFileMaxSize    = flag.Int("log.file.max-size", 100, "max size in megabytes of the log file before rotation")
+	logFileMaxAge     = flag.Int("log.file.max-age", 0, "max age in days to retain rotated log files (0 = forever)")
+	logFileMaxBackups = flag.Int("log.file.max-backups", 0, "max number of rotated log files to retain (0 = all)")
+	logFileCompress   = flag.Bool("log.file.compress", false, "gzip rotated log files")
+
+	debug = flag.String("debug", "", "trace GPIO operations at debug level: api, events, all (overrides $GPIOC_DEBUG)")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usages:\n")
+		fmt.Fprintf(os.Stderr, "  %s [flags] dump\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "  %s [flags] get <pin>\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "  %s [flags] set <pin> <value>\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	logger, err := newLogger()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	slog.SetDefault(logger)
+
+	os.Exit(run())
+}
+
+func newLogger() (*slog.Logger, error) {
+	format, err := xcli.ParseLogFormat(*logFormat)
+	if err != nil {
+		return nil, err
+	}
+	level, err := xcli.ParseLogLevel(*logLevel)
+	if err != nil {
+		return nil, err
+	}
+	color, err := xcli.ParseColorMode(*logColor)
+	if err != nil {
+		return nil, err
+	}
+
+	return xcli.NewLogger(xcli.LogConfig{
+		Writer: os.Stderr,
+		Format: format,
+		Level:  level,
+		Color:  color,
+		File: xcli.LogFileConfig{
+			Path:       *logFile,
+			MaxSize:    *logFileMaxSize,
+			MaxAge:     *logFileMaxAge,
+			MaxBackups: *logFileMaxBackups,
+			Compress:   *logFileCompress,
+		},
+	}), nil
+}
+
+func run() int {
+	switch flag.Arg(0) {
+	case "dump", "get", "set":
+		var registerOpts []gpiodriver.Option
+		if *debug != "" {
+			level, err := gpiodriver.ParseTraceLevel(*debug)
+			if err != nil {
+				slog.Error(err.Error())
+				return 2
+			}
+			registerOpts = append(registerOpts, gpiodriver.WithDebug(level))
+		}
+
+		if err := gpiodriver.Register(registerOpts...); err != nil {
+			slog.Error("failed to register gpiochips", "err", err)
+			return 1
+		}
+
+		if flag.Arg(0) == "dump" {
+			return 0
+		}
+
+		pin := gpioreg.ByName(flag.Arg(1))
+		if pin == nil {
+			slog.Error("invalid pin", "pin", flag.Arg(1))
+			return 1
+		}
+
+		switch flag.Arg(0) {
+		case "get":
+			if err := pin.In(gpio.PullNoChange, gpio.NoEdge); err != nil {
+				slog.Error("failed to set pin as input", "err", err)
+				return 1
+			}
+
+			switch pin.Read() {
+			case gpio.High:
+				fmt.Println("1")
+			case gpio.Low:
+				fmt.Println("0")
+			}
+
+		case "set":
+			value, err := strconv.ParseBool(flag.Arg(2))
+			if err != nil {
+				slog.Error("failed to parse value", "err", err)
+				return 1
+			}
+
+			if err := pin.Out(gpio.Level(value)); err != nil {
+				slog.Error("failed to set pin as output", "err", err)
+				return 1
+			}
+		}
+
+		return 0
+	}
+
+	flag.Usage()
+	slog.Error("invalid command", "cmd", flag.Arg(0))
+	return 1
+}
@@ -0,0 +1,112 @@
+package gpiodriver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/warthog618/go-gpiocdev"
+	"periph.io/x/conn/v3/gpio"
+)
+
+// defaultEventBuffer is the ring size used when EdgeOptions.EventBuffer is
+// left unset.
+const defaultEventBuffer = 16
+
+// EdgeOptions configures the hardware edge detection set up by
+// EdgePin.InWithOptions.
+type EdgeOptions struct {
+	// DebouncePeriod, if non-zero, asks the kernel to debounce the line in
+	// hardware, only reporting an edge once the level has been stable for
+	// this long. This requires uAPI v2 (Linux 5.10+).
+	DebouncePeriod time.Duration
+
+	// EventBuffer sets the size of the ring buffer used to hold edge events
+	// between calls to NextEdge/WaitForEdge. If zero, a small default is
+	// used. Events beyond the buffer's capacity are not queued forever: the
+	// oldest one is dropped to make room, and the drop is counted in
+	// DroppedEvents.
+	EventBuffer int
+}
+
+// EdgeEvent is a single edge transition reported by the kernel.
+type EdgeEvent struct {
+	// Level is the line's level after the transition.
+	Level gpio.Level
+	// Timestamp is when the kernel detected the edge, as a duration since an
+	// unspecified epoch (CLOCK_MONOTONIC). It is only meaningful relative to
+	// other EdgeEvent.Timestamp values from the same pin.
+	Timestamp time.Duration
+	// Seq is the sequence number of this event on the line, as assigned by
+	// the kernel. A gap between consecutive Seq values observed via NextEdge
+	// indicates events were dropped; see DroppedEvents.
+	Seq uint32
+}
+
+// EdgePin is implemented by pins that can expose hardware debouncing and
+// individual edge events, beyond the boolean WaitForEdge offered by
+// gpio.PinIn.
+type EdgePin interface {
+	gpio.PinIn
+
+	// InWithOptions is like In, but additionally configures hardware
+	// debouncing and the edge event ring buffer.
+	InWithOptions(pull gpio.Pull, edge gpio.Edge, opts EdgeOptions) error
+
+	// NextEdge blocks until an edge event is available or ctx is done,
+	// returning the oldest undelivered event.
+	NextEdge(ctx context.Context) (EdgeEvent, error)
+
+	// DroppedEvents returns the number of edge events dropped so far because
+	// they weren't consumed from the ring buffer quickly enough.
+	DroppedEvents() uint32
+}
+
+var _ EdgePin = (*pinAdapter)(nil)
+
+// InWithOptions configures the pin as an input with hardware debouncing and/or
+// a custom event ring buffer size, then behaves like In.
+func (p *pinAdapter) InWithOptions(pull gpio.Pull, edge gpio.Edge, opts EdgeOptions) error {
+	bufSize := opts.EventBuffer
+	if bufSize <= 0 {
+		bufSize = defaultEventBuffer
+	}
+	ring := make(chan gpiocdev.LineEvent, bufSize)
+	p.edgeRing.Store(&ring)
+
+	return p.in(pull, edge, opts.DebouncePeriod)
+}
+
+// NextEdge implements EdgePin.
+func (p *pinAdapter) NextEdge(ctx context.Context) (EdgeEvent, error) {
+	ring := *p.edgeRing.Load()
+
+	select {
+	case event := <-ring:
+		return newEdgeEvent(event), nil
+	case <-ctx.Done():
+		return EdgeEvent{}, ctx.Err()
+	}
+}
+
+// DroppedEvents implements EdgePin.
+func (p *pinAdapter) DroppedEvents() uint32 {
+	return p.edgeDropped.Load()
+}
+
+func newEdgeEvent(event gpiocdev.LineEvent) EdgeEvent {
+	level := gpio.Low
+	if event.Type == gpiocdev.LineEventRisingEdge {
+		level = gpio.High
+	}
+	return EdgeEvent{
+		Level:     level,
+		Timestamp: event.Timestamp,
+		Seq:       event.Seqno,
+	}
+}
+
+// String implements fmt.Stringer.
+func (e EdgeEvent) String() string {
+	return fmt.Sprintf("%s@%s#%d", e.Level, e.Timestamp, e.Seq)
+}
@@ -0,0 +1,100 @@
+package gpiodriver
+
+import (
+	"fmt"
+
+	"github.com/warthog618/go-gpiocdev"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/pin"
+)
+
+// lineOption is satisfied by every gpiocdev option SetFunc composes; it lets
+// a single slice of options serve both RequestLine (for a line that hasn't
+// been opened yet) and Reconfigure (for one that has).
+type lineOption interface {
+	gpiocdev.LineReqOption
+	gpiocdev.LineConfigOption
+}
+
+// funcFromInfo derives the pin's current function from its live LineInfo,
+// mirroring what the bcm283x pin.PinFunc implementation exposes.
+func funcFromInfo(info gpiocdev.LineInfo) pin.Func {
+	switch info.Config.Direction {
+	case gpiocdev.LineDirectionInput:
+		switch info.Config.Bias {
+		case gpiocdev.LineBiasPullUp:
+			return gpio.IN_HIGH
+		case gpiocdev.LineBiasPullDown:
+			return gpio.IN_LOW
+		case gpiocdev.LineBiasDisabled:
+			return gpio.FLOAT
+		default:
+			return gpio.IN
+		}
+
+	case gpiocdev.LineDirectionOutput:
+		// LineInfo doesn't expose the value an output line is currently
+		// driven to, only its direction and drive, so OUT_HIGH/OUT_LOW
+		// (which SetFunc can still request) can't be recovered here.
+		if info.Config.Drive == gpiocdev.LineDriveOpenDrain {
+			return gpio.OUT_OC
+		}
+		return gpio.OUT
+
+	default:
+		// The line hasn't been requested yet; fall back to its name.
+		return pin.Func(info.Name).Generalize()
+	}
+}
+
+// supportedFuncsFor returns every function the pin could be switched to,
+// always leading with its current function (see funcFromInfo). Before the
+// line has been requested, its direction is unknown, so every function it
+// could plausibly support is returned instead.
+func supportedFuncsFor(info gpiocdev.LineInfo) []pin.Func {
+	current := funcFromInfo(info)
+
+	switch info.Config.Direction {
+	case gpiocdev.LineDirectionInput:
+		return []pin.Func{current, gpio.IN, gpio.IN_HIGH, gpio.IN_LOW, gpio.FLOAT}
+	case gpiocdev.LineDirectionOutput:
+		return []pin.Func{current, gpio.OUT, gpio.OUT_OC, gpio.OUT_HIGH, gpio.OUT_LOW}
+	default:
+		return []pin.Func{
+			current,
+			gpio.IN,
+			gpio.IN_HIGH,
+			gpio.IN_LOW,
+			gpio.OUT,
+			gpio.OUT_OC,
+			gpio.OUT_HIGH,
+			gpio.OUT_LOW,
+			gpio.FLOAT,
+		}
+	}
+}
+
+// reconfigureOptionsForFunc converts a requested pin.Func into the gpiocdev
+// options needed to apply it in a single atomic request.
+func reconfigureOptionsForFunc(f pin.Func) ([]lineOption, error) {
+	switch f {
+	case gpio.IN:
+		return []lineOption{gpiocdev.AsInput, gpiocdev.WithBiasAsIs}, nil
+	case gpio.IN_HIGH:
+		return []lineOption{gpiocdev.AsInput, gpiocdev.WithPullUp}, nil
+	case gpio.IN_LOW:
+		return []lineOption{gpiocdev.AsInput, gpiocdev.WithPullDown}, nil
+	case gpio.FLOAT:
+		return []lineOption{gpiocdev.AsInput, gpiocdev.WithBiasDisabled}, nil
+	case gpio.OUT:
+		return []lineOption{gpiocdev.AsOutput(0), gpiocdev.AsPushPull}, nil
+	case gpio.OUT_HIGH:
+		return []lineOption{gpiocdev.AsOutput(1), gpiocdev.AsPushPull}, nil
+	case gpio.OUT_LOW:
+		return []lineOption{gpiocdev.AsOutput(0), gpiocdev.AsPushPull}, nil
+	case gpio.OUT_OC:
+		return []lineOption{gpiocdev.AsOutput(0), gpiocdev.AsOpenDrain}, nil
+	default:
+		return nil, fmt.Errorf("unsupported function %q", f)
+	}
+}
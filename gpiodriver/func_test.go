@@ -0,0 +1,195 @@
+package gpiodriver
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/warthog618/go-gpiocdev"
+	"github.com/warthog618/go-gpiosim"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/pin"
+)
+
+func TestFuncFromInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		info gpiocdev.LineInfo
+		want pin.Func
+	}{
+		{
+			name: "input",
+			info: gpiocdev.LineInfo{Config: gpiocdev.LineConfig{Direction: gpiocdev.LineDirectionInput}},
+			want: gpio.IN,
+		},
+		{
+			name: "input pull-up",
+			info: gpiocdev.LineInfo{Config: gpiocdev.LineConfig{
+				Direction: gpiocdev.LineDirectionInput,
+				Bias:      gpiocdev.LineBiasPullUp,
+			}},
+			want: gpio.IN_HIGH,
+		},
+		{
+			name: "input pull-down",
+			info: gpiocdev.LineInfo{Config: gpiocdev.LineConfig{
+				Direction: gpiocdev.LineDirectionInput,
+				Bias:      gpiocdev.LineBiasPullDown,
+			}},
+			want: gpio.IN_LOW,
+		},
+		{
+			name: "input bias disabled",
+			info: gpiocdev.LineInfo{Config: gpiocdev.LineConfig{
+				Direction: gpiocdev.LineDirectionInput,
+				Bias:      gpiocdev.LineBiasDisabled,
+			}},
+			want: gpio.FLOAT,
+		},
+		{
+			name: "output",
+			info: gpiocdev.LineInfo{Config: gpiocdev.LineConfig{Direction: gpiocdev.LineDirectionOutput}},
+			want: gpio.OUT,
+		},
+		{
+			// LineInfo doesn't expose an output line's driven value, only its
+			// direction/drive, so bias (which only matters for inputs) must
+			// not change the reported function here.
+			name: "output pull-up",
+			info: gpiocdev.LineInfo{Config: gpiocdev.LineConfig{
+				Direction: gpiocdev.LineDirectionOutput,
+				Bias:      gpiocdev.LineBiasPullUp,
+			}},
+			want: gpio.OUT,
+		},
+		{
+			name: "output open-drain",
+			info: gpiocdev.LineInfo{Config: gpiocdev.LineConfig{
+				Direction: gpiocdev.LineDirectionOutput,
+				Drive:     gpiocdev.LineDriveOpenDrain,
+			}},
+			want: gpio.OUT_OC,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := funcFromInfo(tt.info); got != tt.want {
+				t.Errorf("funcFromInfo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSupportedFuncsFor(t *testing.T) {
+	input := gpiocdev.LineInfo{Config: gpiocdev.LineConfig{Direction: gpiocdev.LineDirectionInput}}
+	got := supportedFuncsFor(input)
+	if len(got) == 0 || got[0] != gpio.IN {
+		t.Errorf("supportedFuncsFor(input) = %v, want current func %v first", got, gpio.IN)
+	}
+
+	unknown := gpiocdev.LineInfo{}
+	got = supportedFuncsFor(unknown)
+	if len(got) == 0 {
+		t.Errorf("supportedFuncsFor(unknown) returned no funcs")
+	}
+}
+
+func TestReconfigureOptionsForFunc(t *testing.T) {
+	tests := []struct {
+		name    string
+		f       pin.Func
+		wantErr bool
+	}{
+		{name: "in", f: gpio.IN},
+		{name: "in high", f: gpio.IN_HIGH},
+		{name: "in low", f: gpio.IN_LOW},
+		{name: "float", f: gpio.FLOAT},
+		{name: "out", f: gpio.OUT},
+		{name: "out high", f: gpio.OUT_HIGH},
+		{name: "out low", f: gpio.OUT_LOW},
+		{name: "out open-drain", f: gpio.OUT_OC},
+		{name: "unsupported", f: pin.Func("SPI0_MOSI"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := reconfigureOptionsForFunc(tt.f)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("reconfigureOptionsForFunc(%v) = nil error, want error", tt.f)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("reconfigureOptionsForFunc(%v) returned error: %v", tt.f, err)
+			}
+			if len(options) == 0 {
+				t.Fatalf("reconfigureOptionsForFunc(%v) returned no options", tt.f)
+			}
+		})
+	}
+}
+
+// TestSetFunc drives SetFunc against a gpio-sim simulated chip, covering both
+// the not-yet-opened (RequestLine) and already-opened (Reconfigure) paths.
+// It requires the gpio-sim kernel module (Linux 5.19+, CONFIG_GPIO_SIM) and
+// is typically root-only, so it skips rather than fails where unavailable.
+func TestSetFunc(t *testing.T) {
+	sim, err := gpiosim.NewSimpleton(4)
+	if err != nil {
+		t.Skipf("gpio-sim unavailable: %v", err)
+	}
+	defer sim.Close()
+
+	chip, err := gpiocdev.NewChip(sim.DevPath())
+	if err != nil {
+		t.Fatalf("failed to open simulated chip: %v", err)
+	}
+	defer chip.Close()
+
+	newAdapter := func(t *testing.T, offset int) *pinAdapter {
+		t.Helper()
+		info, err := chip.LineInfo(offset)
+		if err != nil {
+			t.Fatalf("LineInfo(%d): %v", offset, err)
+		}
+		return newPinAdapter(chip, info, slog.Default(), nil)
+	}
+
+	t.Run("not yet opened", func(t *testing.T) {
+		p := newAdapter(t, 0)
+		defer p.Halt()
+
+		if err := p.SetFunc(gpio.OUT_HIGH); err != nil {
+			t.Fatalf("SetFunc(OUT_HIGH) on an unopened pin: %v", err)
+		}
+		// LineInfo can't report the driven value, so Func() can only ever
+		// round-trip back to OUT, not OUT_HIGH; check the actual level via
+		// the simulator instead.
+		if got := p.Func(); got != gpio.OUT {
+			t.Errorf("Func() = %v, want %v", got, gpio.OUT)
+		}
+		if level, err := sim.Level(0); err != nil || level != 1 {
+			t.Errorf("simulated level = (%d, %v), want (1, nil)", level, err)
+		}
+	})
+
+	t.Run("already opened", func(t *testing.T) {
+		p := newAdapter(t, 1)
+		defer p.Halt()
+
+		if err := p.In(gpio.PullNoChange, gpio.NoEdge); err != nil {
+			t.Fatalf("In() to open the line first: %v", err)
+		}
+
+		if err := p.SetFunc(gpio.OUT_LOW); err != nil {
+			t.Fatalf("SetFunc(OUT_LOW) on an already-opened pin: %v", err)
+		}
+		if got := p.Func(); got != gpio.OUT {
+			t.Errorf("Func() = %v, want %v", got, gpio.OUT)
+		}
+		if level, err := sim.Level(1); err != nil || level != 0 {
+			t.Errorf("simulated level = (%d, %v), want (0, nil)", level, err)
+		}
+	})
+}
@@ -15,21 +15,105 @@ import (
 	"periph.io/x/conn/v3/physic"
 	"periph.io/x/conn/v3/pin"
 	"periph.io/x/conn/v3/pin/pinreg"
+
+	"libdb.so/periph-gpioc/gpiodriver/iioadc"
 )
 
-// Register initializes all available gpiochip devices.
-func Register(options ...gpiocdev.ChipOption) error {
+// registerOptions holds the configuration assembled by a Register call's
+// Option arguments.
+type registerOptions struct {
+	chipOptions []gpiocdev.ChipOption
+	iio         bool
+	pwmMapping  map[string]PWMChannel
+	debug       TraceLevel
+	debugSet    bool
+}
+
+// Option configures a call to Register.
+type Option func(*registerOptions)
+
+// WithChipOptions forwards the given options to gpiocdev.NewChip for every
+// gpiochip opened by Register.
+func WithChipOptions(options ...gpiocdev.ChipOption) Option {
+	return func(o *registerOptions) {
+		o.chipOptions = append(o.chipOptions, options...)
+	}
+}
+
+// WithIIO additionally brings up the analog pins exposed by the Linux IIO
+// subsystem (see the iioadc subpackage) alongside the digital gpiochip
+// lines.
+func WithIIO() Option {
+	return func(o *registerOptions) {
+		o.iio = true
+	}
+}
+
+// WithPWMMapping tells PWM calls which pins have a hardware PWM channel
+// muxed to them, keyed by pin name (e.g. "GPIO18"). This is board-specific
+// and can't be derived from the gpiochip or sysfs PWM trees alone, so it
+// must be supplied by the caller. Pins not present in the mapping fall back
+// to software PWM.
+func WithPWMMapping(mapping map[string]PWMChannel) Option {
+	return func(o *registerOptions) {
+		if o.pwmMapping == nil {
+			o.pwmMapping = make(map[string]PWMChannel, len(mapping))
+		}
+		for name, ch := range mapping {
+			o.pwmMapping[name] = ch
+		}
+	}
+}
+
+// WithDebug overrides $GPIOC_DEBUG, installing a tracing gpio.PinIO wrapper
+// around every pin registered by this call at the given level. Pass
+// TraceOff to force tracing off regardless of the environment.
+func WithDebug(level TraceLevel) Option {
+	return func(o *registerOptions) {
+		o.debug = level
+		o.debugSet = true
+	}
+}
+
+// Register initializes all available gpiochip devices, and, if WithIIO is
+// given, the ADC channels exposed through the Linux IIO subsystem.
+func Register(options ...Option) error {
+	var cfg registerOptions
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	if len(cfg.pwmMapping) > 0 {
+		checkPWMMapping(cfg.pwmMapping, slog.Default().With("driver", "gpiodriver"))
+	}
+
+	debug := cfg.debug
+	if !cfg.debugSet {
+		debug = traceLevelFromEnv()
+	}
+
 	var errs []error
 	for _, name := range gpiocdev.Chips() {
-		if err := RegisterChip(name); err != nil {
+		if err := registerChip(name, cfg.pwmMapping, debug, cfg.chipOptions...); err != nil {
 			errs = append(errs, fmt.Errorf("failed to initialize gpiochip %q: %w", name, err))
 		}
 	}
+
+	if cfg.iio {
+		if err := iioadc.Register(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to initialize iio adc: %w", err))
+		}
+	}
+
 	return errors.Join(errs...)
 }
 
 // RegisterChip initializes the gpiochip device with the given name.
 func RegisterChip(name string, options ...gpiocdev.ChipOption) error {
+	return registerChip(name, nil, traceLevelFromEnv(), options...)
+}
+
+func registerChip(name string, pwmMapping map[string]PWMChannel, debug TraceLevel, options ...gpiocdev.ChipOption) error {
 	chip, err := gpiocdev.NewChip(name, options...)
 	if err != nil {
 		return fmt.Errorf("failed to open gpiochip %q: %w", name, err)
@@ -64,7 +148,12 @@ func RegisterChip(name string, options ...gpiocdev.ChipOption) error {
 		}
 
 		logger.Debug("found line")
-		pins = append(pins, newPinAdapter(chip, info, logger))
+
+		var pwm *PWMChannel
+		if ch, ok := pwmMapping[info.Name]; ok {
+			pwm = &ch
+		}
+		pins = append(pins, newPinAdapter(chip, info, logger, pwm))
 	}
 
 	if err := pinreg.Register(strings.ToUpper(name), [][]pin.Pin{pins}); err != nil {
@@ -73,7 +162,13 @@ func RegisterChip(name string, options ...gpiocdev.ChipOption) error {
 
 	for _, pin := range pins {
 		adapter := pin.(*pinAdapter)
-		if err := gpioreg.Register(adapter); err != nil {
+
+		var target gpio.PinIO = adapter
+		if debug != TraceOff {
+			target = newTracePin(adapter, debug)
+		}
+
+		if err := gpioreg.Register(target); err != nil {
 			return fmt.Errorf("failed to register pin %q: %w", adapter.Name(), err)
 		}
 	}
@@ -82,11 +177,16 @@ func RegisterChip(name string, options ...gpiocdev.ChipOption) error {
 }
 
 type pinAdapter struct {
-	logger *slog.Logger                      // const
-	chip   *gpiocdev.Chip                    // const
-	edge   chan struct{}                     // const
-	line   atomic.Pointer[gpiocdev.Line]     // const, nil if not opened
-	info   atomic.Pointer[gpiocdev.LineInfo] // const
+	logger      *slog.Logger                            // const
+	chip        *gpiocdev.Chip                          // const
+	pwm         *PWMChannel                             // const, nil if no hardware PWM channel is mapped to this pin
+	edgeRing    atomic.Pointer[chan gpiocdev.LineEvent] // ring buffer of recent edge events
+	edgeDropped atomic.Uint32                           // count of edge events dropped because the ring was full
+	line        atomic.Pointer[gpiocdev.Line]           // const, nil if not opened
+	info        atomic.Pointer[gpiocdev.LineInfo]       // const
+	streamSink  atomic.Pointer[chan gpiocdev.LineEvent] // set while a gpiostream capture is in progress
+	pwmActive   atomic.Pointer[PWMChannel]              // set while p.pwm is exported and enabled, for Halt to clean up
+	softPWM     atomic.Pointer[softPWMLoop]             // set while a software PWM goroutine is running
 }
 
 var (
@@ -97,12 +197,25 @@ var (
 	_ gpio.PinOut   = (*pinAdapter)(nil)
 )
 
-func newPinAdapter(chip *gpiocdev.Chip, info gpiocdev.LineInfo, logger *slog.Logger) *pinAdapter {
+// pinAdapterUnwrapper is implemented by gpio.PinIO values that either are, or
+// wrap, a *pinAdapter (e.g. tracePin), letting internal code like
+// NewLineGroup recover the underlying adapter regardless of wrapping.
+type pinAdapterUnwrapper interface {
+	unwrapPinAdapter() *pinAdapter
+}
+
+var _ pinAdapterUnwrapper = (*pinAdapter)(nil)
+
+func (p *pinAdapter) unwrapPinAdapter() *pinAdapter { return p }
+
+func newPinAdapter(chip *gpiocdev.Chip, info gpiocdev.LineInfo, logger *slog.Logger, pwm *PWMChannel) *pinAdapter {
 	p := &pinAdapter{
 		logger: logger,
 		chip:   chip,
-		edge:   make(chan struct{}),
+		pwm:    pwm,
 	}
+	ring := make(chan gpiocdev.LineEvent, defaultEventBuffer)
+	p.edgeRing.Store(&ring)
 	p.info.Store(&info)
 	return p
 }
@@ -159,9 +272,29 @@ func (p *pinAdapter) handleInfoChange(event gpiocdev.LineInfoChangeEvent) {
 func (p *pinAdapter) handleEvent(event gpiocdev.LineEvent) {
 	switch event.Type {
 	case gpiocdev.LineEventRisingEdge, gpiocdev.LineEventFallingEdge:
+		ring := *p.edgeRing.Load()
 		select {
-		case p.edge <- struct{}{}:
+		case ring <- event:
 		default:
+			// The ring is full: drop the oldest event to make room, rather
+			// than the newest, so NextEdge/WaitForEdge stay roughly
+			// real-time instead of replaying stale history.
+			select {
+			case <-ring:
+			default:
+			}
+			select {
+			case ring <- event:
+			default:
+			}
+			p.edgeDropped.Add(1)
+		}
+
+		if sink := p.streamSink.Load(); sink != nil {
+			select {
+			case *sink <- event:
+			default:
+			}
 		}
 	}
 }
@@ -180,13 +313,24 @@ func (p *pinAdapter) String() string {
 // device but it should stop motion, sensing loop, light emission or PWM
 // output and go back into an inert state.
 func (p *pinAdapter) Halt() error {
-	line := p.line.Load()
-	if line == nil {
-		return nil
-	}
+	p.stopSoftPWM()
 
 	var errs []error
 
+	if ch := p.pwmActive.Swap(nil); ch != nil {
+		if err := ch.disable(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to disable pwm: %w", err))
+		}
+		if err := ch.unexport(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to unexport pwm: %w", err))
+		}
+	}
+
+	line := p.line.Swap(nil)
+	if line == nil {
+		return errors.Join(errs...)
+	}
+
 	if err := line.Close(); err != nil {
 		errs = append(errs, fmt.Errorf("failed to close line: %w", err))
 	}
@@ -221,80 +365,34 @@ func (p *pinAdapter) Function() string {
 	return string(p.Func())
 }
 
-// Func returns the pin's current function.
-//
-// The returned value may be specialized or generalized, depending on the
-// actual port. For example it will likely be generalized for ports served
-// over USB (like a FT232H with D0 set as SPI_MOSI) but specialized for
-// ports on the base board (like a RPi3 with GPIO10 set as SPI0_MOSI).
+// Func returns the pin's current function, derived from the live LineInfo
+// rather than the pin's name: input vs output, with open-drain, pull-up and
+// pull-down all reflected.
 func (p *pinAdapter) Func() pin.Func {
-	return pin.Func(p.Name()).Generalize()
+	return funcFromInfo(*p.info.Load())
 }
 
 // SupportedFuncs returns the possible functions this pin support.
 //
 // Do not mutate the returned slice.
 func (p *pinAdapter) SupportedFuncs() []pin.Func {
-	return []pin.Func{
-		gpio.IN,
-		gpio.IN_HIGH,
-		gpio.IN_LOW,
-		gpio.OUT,
-		gpio.OUT_OC,
-		gpio.OUT_HIGH,
-		gpio.OUT_LOW,
-		gpio.FLOAT,
-	}
-
-	// funcs := []pin.Func{p.Func()}
-	// info := p.info.Load()
-	//
-	// switch info.Config.Direction {
-	// case gpiocdev.LineDirectionInput:
-	// 	funcs = append(funcs, gpio.IN)
-	//
-	// 	switch info.Config.Bias {
-	// 	case gpiocdev.LineBiasPullUp:
-	// 		// Pull up means default high.
-	// 		funcs = append(funcs, gpio.IN_HIGH)
-	// 	case gpiocdev.LineBiasPullDown:
-	// 		// Pull down means default low.
-	// 		funcs = append(funcs, gpio.IN_LOW)
-	// 	}
-	//
-	// case gpiocdev.LineDirectionOutput:
-	// 	switch info.Config.Drive {
-	// 	case gpiocdev.LineDrivePushPull:
-	// 		// Drive aka push-pull.
-	// 		funcs = append(funcs, gpio.OUT)
-	// 	case gpiocdev.LineDriveOpenDrain:
-	// 		// Open collector/drain aka open-drain.
-	// 		funcs = append(funcs, gpio.OUT_OC)
-	// 	}
-	//
-	// 	switch info.Config.Bias {
-	// 	case gpiocdev.LineBiasPullUp:
-	// 		funcs = append(funcs, gpio.OUT_HIGH)
-	// 	case gpiocdev.LineBiasPullDown:
-	// 		funcs = append(funcs, gpio.OUT_LOW)
-	// 	case gpiocdev.LineBiasDisabled:
-	// 		funcs = append(funcs, gpio.FLOAT)
-	// 	}
-	// }
-	//
-	// p.logger.Debug(
-	// 	"pin supported functions",
-	// 	"funcs", funcs)
-	//
-	// return []pin.Func{p.Func()}
+	funcs := supportedFuncsFor(*p.info.Load())
+	p.logger.Debug(
+		"pin supported functions",
+		"funcs", funcs)
+	return funcs
 }
 
 // SetFunc sets the pin function.
 //
 // Example use is to reallocate a RPi3's GPIO14 active function between
 // UART0_TX and UART1_TX.
+//
+// Unlike In/Out, SetFunc composes direction, drive and bias into a single
+// Reconfigure (or RequestLine, if the line hasn't been opened yet) call, so
+// the whole change takes effect atomically.
 func (p *pinAdapter) SetFunc(f pin.Func) error {
-	_, err := p.initPin()
+	options, err := reconfigureOptionsForFunc(f)
 	if err != nil {
 		return err
 	}
@@ -303,61 +401,55 @@ func (p *pinAdapter) SetFunc(f pin.Func) error {
 		"set pin function",
 		"func", f)
 
-	pin := p.line.Load()
+	reqOptions := make([]gpiocdev.LineReqOption, len(options))
+	cfgOptions := make([]gpiocdev.LineConfigOption, len(options))
+	for i, o := range options {
+		reqOptions[i] = o
+		cfgOptions[i] = o
+	}
 
-	// https://github.com/periph/host/blob/522a3cb6e99e9649daf291bfb7b097219409a813/bcm283x/gpio.go#L319
-	switch f {
-	case gpio.IN:
-		return p.In(gpio.PullNoChange, gpio.NoEdge)
-	case gpio.IN_LOW:
-		return p.In(gpio.PullDown, gpio.NoEdge)
-	case gpio.IN_HIGH:
-		return p.In(gpio.PullUp, gpio.NoEdge)
-	case gpio.OUT:
-		err = pin.Reconfigure(gpiocdev.AsOutput(), gpiocdev.AsPushPull)
-	case gpio.OUT_OC:
-		err = pin.Reconfigure(gpiocdev.AsOutput(), gpiocdev.AsOpenDrain)
-	case gpio.OUT_HIGH:
-		return p.Out(gpio.High)
-	case gpio.OUT_LOW:
-		return p.Out(gpio.Low)
-	default:
-		err = fmt.Errorf("unsupported function %q", f)
+	initialized, err := p.initPin(reqOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to initialize pin: %w", err)
+	}
+	if initialized {
+		return nil
+	}
+
+	line := p.line.Load()
+	if err := line.Reconfigure(cfgOptions...); err != nil {
+		return fmt.Errorf("failed to set pin function: %w", err)
 	}
 
-	return err
+	return nil
 }
 
 func (p *pinAdapter) In(pull gpio.Pull, edge gpio.Edge) error {
-	var cBias gpiocdev.LineBias
-	switch pull {
-	case gpio.PullNoChange:
-		cBias = gpiocdev.WithBiasAsIs
-	case gpio.PullUp:
-		cBias = gpiocdev.WithPullUp
-	case gpio.PullDown:
-		cBias = gpiocdev.WithPullDown
-	case gpio.Float:
-		cBias = gpiocdev.WithBiasDisabled
-	default:
-		return fmt.Errorf("unsupported pull %q", pull)
+	return p.in(pull, edge, 0)
+}
+
+// in is the shared implementation behind In and InWithOptions. A zero
+// debounce means hardware debouncing is left disabled.
+func (p *pinAdapter) in(pull gpio.Pull, edge gpio.Edge, debounce time.Duration) error {
+	cBias, err := pullToBias(pull)
+	if err != nil {
+		return err
 	}
 
-	var cEdge gpiocdev.LineEdge
-	switch edge {
-	case gpio.NoEdge:
-		cEdge = gpiocdev.WithoutEdges
-	case gpio.RisingEdge:
-		cEdge = gpiocdev.WithRisingEdge
-	case gpio.FallingEdge:
-		cEdge = gpiocdev.WithFallingEdge
-	case gpio.BothEdges:
-		cEdge = gpiocdev.WithBothEdges
-	default:
-		return fmt.Errorf("unsupported edge %q", edge)
+	cEdge, err := edgeToLineEdge(edge)
+	if err != nil {
+		return err
+	}
+
+	reqOptions := []gpiocdev.LineReqOption{gpiocdev.AsInput, cBias, cEdge}
+	cfgOptions := []gpiocdev.LineConfigOption{gpiocdev.AsInput, cBias, cEdge}
+	if debounce > 0 {
+		d := gpiocdev.WithDebounce(debounce)
+		reqOptions = append(reqOptions, d)
+		cfgOptions = append(cfgOptions, d)
 	}
 
-	initialized, err := p.initPin(gpiocdev.AsInput, cBias, cEdge)
+	initialized, err := p.initPin(reqOptions...)
 	if err != nil {
 		return fmt.Errorf("failed to initialize pin: %w", err)
 	}
@@ -366,13 +458,43 @@ func (p *pinAdapter) In(pull gpio.Pull, edge gpio.Edge) error {
 	}
 
 	pin := p.line.Load()
-	if err := pin.Reconfigure(gpiocdev.AsInput, cBias, cEdge); err != nil {
+	if err := pin.Reconfigure(cfgOptions...); err != nil {
 		return fmt.Errorf("failed to configure pin as input: %w", err)
 	}
 
 	return nil
 }
 
+func pullToBias(pull gpio.Pull) (gpiocdev.LineBias, error) {
+	switch pull {
+	case gpio.PullNoChange:
+		return gpiocdev.WithBiasAsIs, nil
+	case gpio.PullUp:
+		return gpiocdev.WithPullUp, nil
+	case gpio.PullDown:
+		return gpiocdev.WithPullDown, nil
+	case gpio.Float:
+		return gpiocdev.WithBiasDisabled, nil
+	default:
+		return 0, fmt.Errorf("unsupported pull %q", pull)
+	}
+}
+
+func edgeToLineEdge(edge gpio.Edge) (gpiocdev.LineEdge, error) {
+	switch edge {
+	case gpio.NoEdge:
+		return gpiocdev.WithoutEdges, nil
+	case gpio.RisingEdge:
+		return gpiocdev.WithRisingEdge, nil
+	case gpio.FallingEdge:
+		return gpiocdev.WithFallingEdge, nil
+	case gpio.BothEdges:
+		return gpiocdev.WithBothEdges, nil
+	default:
+		return 0, fmt.Errorf("unsupported edge %q", edge)
+	}
+}
+
 func (p *pinAdapter) Read() gpio.Level {
 	pin := p.line.Load()
 	// The GPIO package just returns Low if the pin is not opened yet.
@@ -411,19 +533,25 @@ func (p *pinAdapter) ReadFast() gpio.Level {
 	return gpio.Level(itob(v & 0b1))
 }
 
+// busySpinThreshold is the duration below which we busy wait instead of
+// relying on the OS timer, which is not precise enough at this scale.
+const busySpinThreshold = 1 * time.Microsecond
+
 func (p *pinAdapter) WaitForEdge(timeout time.Duration) bool {
+	ring := *p.edgeRing.Load()
+
 	if timeout < 0 {
-		<-p.edge
+		<-ring
 		return true
 	}
 
 	// If we're waiting for less than 100µs, just busy wait.
-	if timeout < 1*time.Microsecond {
+	if timeout < busySpinThreshold {
 		// Busy wait.
 		deadline := time.Now().Add(timeout)
 		for time.Now().Before(deadline) {
 			select {
-			case <-p.edge:
+			case <-ring:
 				return true
 			default:
 			}
@@ -435,7 +563,7 @@ func (p *pinAdapter) WaitForEdge(timeout time.Duration) bool {
 	defer timer.Stop()
 
 	select {
-	case <-p.edge:
+	case <-ring:
 		return true
 	case <-timer.C:
 		return false
@@ -490,8 +618,17 @@ func (p *pinAdapter) Out(level gpio.Level) error {
 	return nil
 }
 
+// PWM implements gpio.PinOut.
+//
+// If a hardware PWM channel was mapped to this pin via WithPWMMapping, it is
+// exported and configured through the Linux sysfs PWM ABI. Otherwise, PWM
+// falls back to a software-timed goroutine that bit-bangs the duty cycle by
+// toggling Out, up to softPWMCeiling.
 func (p *pinAdapter) PWM(duty gpio.Duty, f physic.Frequency) error {
-	return errors.New("not implemented")
+	if p.pwm != nil {
+		return p.pwmHardware(*p.pwm, duty, f)
+	}
+	return p.pwmSoftware(duty, f)
 }
 
 func itob(i int) bool {
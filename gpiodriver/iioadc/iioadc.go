@@ -0,0 +1,120 @@
+// Package iioadc registers the analog input channels exposed by the Linux
+// IIO (Industrial I/O) subsystem as periph.io ADC pins.
+//
+// It walks /sys/bus/iio/devices, reading the raw value and scale attributes
+// published by the kernel driver for each channel, following the same
+// sysfs-walking approach embd uses for BeagleBone analog inputs.
+package iioadc
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"periph.io/x/conn/v3/analog"
+	"periph.io/x/conn/v3/pin"
+	"periph.io/x/conn/v3/pin/pinreg"
+)
+
+// defaultSysfsDir is the standard location of the IIO device tree.
+const defaultSysfsDir = "/sys/bus/iio/devices"
+
+var channelFileRE = regexp.MustCompile(`^in_voltage(\d+)_raw$`)
+
+var (
+	mu     sync.Mutex
+	byName = map[string]analog.PinADC{}
+)
+
+// Register walks the default Linux IIO sysfs tree and registers every
+// voltage input channel found as an analog.PinADC.
+func Register() error {
+	return RegisterDir(defaultSysfsDir)
+}
+
+// RegisterDir is like Register but walks the given sysfs directory instead
+// of the default. It mainly exists so tests can point it at a fake tree.
+func RegisterDir(sysfsDir string) error {
+	entries, err := os.ReadDir(sysfsDir)
+	if err != nil {
+		return fmt.Errorf("iioadc: failed to list %q: %w", sysfsDir, err)
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "iio:device") {
+			continue
+		}
+		devicePath := filepath.Join(sysfsDir, entry.Name())
+		if err := registerDevice(devicePath, entry.Name()); err != nil {
+			errs = append(errs, fmt.Errorf("iioadc: failed to register %q: %w", entry.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ByName returns the registered ADC pin with the given name, or nil if none
+// was found.
+func ByName(name string) analog.PinADC {
+	mu.Lock()
+	defer mu.Unlock()
+	return byName[name]
+}
+
+func registerDevice(devicePath, deviceName string) error {
+	files, err := os.ReadDir(devicePath)
+	if err != nil {
+		return fmt.Errorf("failed to list %q: %w", devicePath, err)
+	}
+
+	logger := slog.Default().With(
+		"driver", "iioadc",
+		"device", deviceName)
+
+	if freq, err := readIntFile(filepath.Join(devicePath, "sampling_frequency")); err == nil {
+		logger = logger.With("sampling_frequency", freq)
+	}
+
+	var pins []pin.Pin
+	for _, f := range files {
+		m := channelFileRE.FindStringSubmatch(f.Name())
+		if m == nil {
+			continue
+		}
+
+		channel, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
+		}
+
+		p := newADCPin(devicePath, deviceName, channel, logger.With("channel", channel))
+		logger.Debug("found channel", "channel", channel)
+		pins = append(pins, p)
+	}
+
+	if len(pins) == 0 {
+		return nil
+	}
+
+	mu.Lock()
+	for _, p := range pins {
+		byName[p.Name()] = p.(analog.PinADC)
+	}
+	mu.Unlock()
+
+	return pinreg.Register(strings.ToUpper(deviceName), [][]pin.Pin{pins})
+}
+
+func readIntFile(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
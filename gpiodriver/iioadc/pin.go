@@ -0,0 +1,205 @@
+package iioadc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"periph.io/x/conn/v3/analog"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/pin"
+)
+
+// adcPin adapts a single IIO voltage channel to analog.PinADC.
+type adcPin struct {
+	logger     *slog.Logger
+	devicePath string
+	deviceName string
+	channel    int
+
+	mu      sync.Mutex
+	rawFile *os.File // nil until first Read, closed by Halt
+}
+
+var (
+	_ analog.PinADC = (*adcPin)(nil)
+	_ pin.PinFunc   = (*adcPin)(nil)
+)
+
+func newADCPin(devicePath, deviceName string, channel int, logger *slog.Logger) *adcPin {
+	return &adcPin{
+		logger:     logger,
+		devicePath: devicePath,
+		deviceName: deviceName,
+		channel:    channel,
+	}
+}
+
+// String implements fmt.Stringer.
+func (a *adcPin) String() string {
+	return fmt.Sprintf("%s/voltage%d", a.deviceName, a.channel)
+}
+
+// Halt releases the raw value file handle, if one is open.
+func (a *adcPin) Halt() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.rawFile == nil {
+		return nil
+	}
+	err := a.rawFile.Close()
+	a.rawFile = nil
+	return err
+}
+
+// Name returns the name of the pin.
+func (a *adcPin) Name() string {
+	return fmt.Sprintf("%s_VOLTAGE%d", strings.ToUpper(a.deviceName), a.channel)
+}
+
+// Number returns the channel's index within its IIO device.
+func (a *adcPin) Number() int {
+	return a.channel
+}
+
+// Function returns a user readable string representation of what the pin is
+// configured to do.
+//
+// Deprecated: Use PinFunc.Func. Will be removed in v4.
+func (a *adcPin) Function() string {
+	return string(a.Func())
+}
+
+// Func returns the pin's current function, which is always analog input.
+func (a *adcPin) Func() pin.Func {
+	return pin.Func(a.Name())
+}
+
+// SupportedFuncs returns the possible functions this pin supports.
+func (a *adcPin) SupportedFuncs() []pin.Func {
+	return []pin.Func{a.Func()}
+}
+
+// SetFunc always fails: an IIO ADC channel's function is fixed.
+func (a *adcPin) SetFunc(f pin.Func) error {
+	return fmt.Errorf("iioadc: %s does not support changing function", a)
+}
+
+// fallbackRawMax is the Range upper bound assumed when a channel doesn't
+// expose in_voltageX_raw_available (many simple ADC drivers don't): a
+// 12-bit unsigned range, common among the inexpensive ADCs this package
+// targets.
+//
+// TODO: once we support a driver whose native resolution isn't 12 bits,
+// derive this from something driver-specific instead (e.g. a per-device
+// quirk table) rather than guessing.
+const fallbackRawMax = 1<<12 - 1
+
+// Range returns the maximum supported range [min, max] of the values.
+//
+// The bounds come from the channel's in_voltageX_raw_available file, which
+// the IIO ABI documents as "min step max" for continuously adjustable
+// channels. The electrical tension bounds are left unset; only Raw is
+// meaningful here.
+func (a *adcPin) Range() (analog.Sample, analog.Sample) {
+	min, max, err := a.readRawAvailable()
+	if err != nil {
+		min, max = 0, fallbackRawMax
+	}
+	return analog.Sample{Raw: int32(min)}, analog.Sample{Raw: int32(max)}
+}
+
+// readRawAvailable parses in_voltageX_raw_available's "min step max" (or
+// a discrete "v0 v1 ... vN") format into the overall [min, max] it spans.
+func (a *adcPin) readRawAvailable() (min, max int, err error) {
+	path := filepath.Join(a.devicePath, fmt.Sprintf("in_voltage%d_raw_available", a.channel))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, 0, fmt.Errorf("iioadc: empty %s", path)
+	}
+
+	min, err = strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("iioadc: failed to parse %s: %w", path, err)
+	}
+	max, err = strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("iioadc: failed to parse %s: %w", path, err)
+	}
+	return min, max, nil
+}
+
+// Read returns the current sample for this channel, computing the
+// electrical tension as raw * scale millivolts, per the IIO sysfs ABI.
+func (a *adcPin) Read() (analog.Sample, error) {
+	raw, err := a.readRaw()
+	if err != nil {
+		return analog.Sample{}, fmt.Errorf("iioadc: failed to read %s: %w", a, err)
+	}
+
+	scale, err := a.readScale()
+	if err != nil {
+		return analog.Sample{}, fmt.Errorf("iioadc: failed to read scale for %s: %w", a, err)
+	}
+
+	milliVolts := int64(float64(raw) * scale)
+	return analog.Sample{
+		Raw: int32(raw),
+		V:   physic.ElectricPotential(milliVolts) * physic.MilliVolt,
+	}, nil
+}
+
+func (a *adcPin) readRaw() (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.rawFile == nil {
+		path := filepath.Join(a.devicePath, fmt.Sprintf("in_voltage%d_raw", a.channel))
+		f, err := os.Open(path)
+		if err != nil {
+			return 0, err
+		}
+		a.rawFile = f
+	}
+
+	if _, err := a.rawFile.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	data, err := io.ReadAll(a.rawFile)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+func (a *adcPin) readScale() (float64, error) {
+	path := filepath.Join(a.devicePath, fmt.Sprintf("in_voltage%d_scale", a.channel))
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		// Some drivers share one scale across every channel.
+		path = filepath.Join(a.devicePath, "in_voltage_scale")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	scale, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse scale %q: %w", data, err)
+	}
+	return scale, nil
+}
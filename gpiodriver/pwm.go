@@ -0,0 +1,237 @@
+package gpiodriver
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+)
+
+// pwmSysfsDir is the standard location of the Linux sysfs PWM class tree.
+const pwmSysfsDir = "/sys/class/pwm"
+
+// PWMChannel identifies a hardware PWM output exposed through the Linux
+// sysfs PWM class, e.g. Chip: 0, Channel: 1 refers to
+// /sys/class/pwm/pwmchip0/pwm1. Boards mux these to specific GPIO lines in a
+// way the kernel doesn't expose generically, so the mapping must be supplied
+// by the caller; see WithPWMMapping.
+type PWMChannel struct {
+	Chip    int
+	Channel int
+}
+
+func (c PWMChannel) chipPath() string {
+	return filepath.Join(pwmSysfsDir, fmt.Sprintf("pwmchip%d", c.Chip))
+}
+
+func (c PWMChannel) channelPath() string {
+	return filepath.Join(c.chipPath(), fmt.Sprintf("pwm%d", c.Channel))
+}
+
+// checkPWMMapping logs a warning for every mapped channel whose pwmchip
+// doesn't exist or doesn't expose that many channels, so misconfiguration is
+// visible at Register time rather than on the first PWM call. It never
+// returns an error: an unusable channel is removed from mapping, so the
+// affected pin falls back to software PWM.
+func checkPWMMapping(mapping map[string]PWMChannel, logger *slog.Logger) {
+	for name, ch := range mapping {
+		data, err := os.ReadFile(filepath.Join(ch.chipPath(), "npwm"))
+		if err != nil {
+			logger.Warn("pwm channel unavailable, pin will fall back to software pwm",
+				"pin", name, "chip", ch.Chip, "channel", ch.Channel, "err", err)
+			delete(mapping, name)
+			continue
+		}
+
+		npwm, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil || ch.Channel >= npwm {
+			logger.Warn("pwm channel out of range, pin will fall back to software pwm",
+				"pin", name, "chip", ch.Chip, "channel", ch.Channel, "npwm", strings.TrimSpace(string(data)))
+			delete(mapping, name)
+		}
+	}
+}
+
+func (c PWMChannel) export() error {
+	if _, err := os.Stat(c.channelPath()); err == nil {
+		return nil
+	}
+	if err := os.WriteFile(filepath.Join(c.chipPath(), "export"), []byte(strconv.Itoa(c.Channel)), 0o644); err != nil {
+		return fmt.Errorf("failed to export pwm%d on pwmchip%d: %w", c.Channel, c.Chip, err)
+	}
+	return nil
+}
+
+func (c PWMChannel) unexport() error {
+	if err := os.WriteFile(filepath.Join(c.chipPath(), "unexport"), []byte(strconv.Itoa(c.Channel)), 0o644); err != nil {
+		return fmt.Errorf("failed to unexport pwm%d on pwmchip%d: %w", c.Channel, c.Chip, err)
+	}
+	return nil
+}
+
+// configure sets period and duty_cycle, both in nanoseconds, and enables the
+// channel. The kernel rejects a duty_cycle greater than the current period,
+// so duty_cycle is always zeroed before period is written.
+func (c PWMChannel) configure(periodNs, dutyNs int64) error {
+	path := c.channelPath()
+	if err := os.WriteFile(filepath.Join(path, "duty_cycle"), []byte("0"), 0o644); err != nil {
+		return fmt.Errorf("failed to clear duty_cycle: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "period"), []byte(strconv.FormatInt(periodNs, 10)), 0o644); err != nil {
+		return fmt.Errorf("failed to set period: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "duty_cycle"), []byte(strconv.FormatInt(dutyNs, 10)), 0o644); err != nil {
+		return fmt.Errorf("failed to set duty_cycle: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "enable"), []byte("1"), 0o644); err != nil {
+		return fmt.Errorf("failed to enable channel: %w", err)
+	}
+	return nil
+}
+
+func (c PWMChannel) disable() error {
+	if err := os.WriteFile(filepath.Join(c.channelPath(), "enable"), []byte("0"), 0o644); err != nil {
+		return fmt.Errorf("failed to disable pwm%d on pwmchip%d: %w", c.Channel, c.Chip, err)
+	}
+	return nil
+}
+
+// softPWMCeiling is the highest frequency software PWM is allowed to target.
+// Above this, goroutine scheduling jitter makes the duty cycle unreliable.
+const softPWMCeiling = 1 * physic.KiloHertz
+
+// softPWMLoop tracks a running bit-banged PWM goroutine so it can be
+// stopped, either to reconfigure it or on Halt.
+type softPWMLoop struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// pwmHardware exports and drives ch through the sysfs PWM ABI.
+func (p *pinAdapter) pwmHardware(ch PWMChannel, duty gpio.Duty, freq physic.Frequency) error {
+	p.stopSoftPWM()
+
+	if freq == 0 {
+		freq = 1 * physic.KiloHertz
+	}
+
+	period := freq.Period()
+	dutyNs := period.Nanoseconds() * int64(duty) / int64(gpio.DutyMax)
+
+	if err := ch.export(); err != nil {
+		return fmt.Errorf("gpiodriver: %w", err)
+	}
+	if err := ch.configure(period.Nanoseconds(), dutyNs); err != nil {
+		return fmt.Errorf("gpiodriver: failed to configure pwm%d on pwmchip%d: %w", ch.Channel, ch.Chip, err)
+	}
+	p.pwmActive.Store(&ch)
+
+	return nil
+}
+
+// pwmSoftware bit-bangs the requested duty cycle by toggling Out on a
+// dedicated goroutine, reusing the busy-wait/timer thresholding WaitForEdge
+// uses for precise short sleeps.
+func (p *pinAdapter) pwmSoftware(duty gpio.Duty, freq physic.Frequency) error {
+	if freq == 0 {
+		freq = softPWMCeiling
+	}
+	if freq > softPWMCeiling {
+		return fmt.Errorf("gpiodriver: software pwm frequency %s exceeds the %s ceiling for bit-banged output", freq, softPWMCeiling)
+	}
+
+	period := freq.Period()
+	high := period * time.Duration(duty) / time.Duration(gpio.DutyMax)
+	low := period - high
+
+	p.stopSoftPWM()
+
+	loop := &softPWMLoop{stop: make(chan struct{}), done: make(chan struct{})}
+	p.softPWM.Store(loop)
+
+	go p.runSoftPWM(loop, high, low)
+
+	return nil
+}
+
+func (p *pinAdapter) runSoftPWM(loop *softPWMLoop, high, low time.Duration) {
+	defer close(loop.done)
+
+	if high <= 0 {
+		p.setSoftPWMLevel(gpio.Low)
+		return
+	}
+	if low <= 0 {
+		p.setSoftPWMLevel(gpio.High)
+		return
+	}
+
+	deadline := time.Now()
+	for {
+		p.setSoftPWMLevel(gpio.High)
+		deadline = deadline.Add(high)
+		if !waitUntilOrStop(deadline, loop.stop) {
+			return
+		}
+
+		p.setSoftPWMLevel(gpio.Low)
+		deadline = deadline.Add(low)
+		if !waitUntilOrStop(deadline, loop.stop) {
+			return
+		}
+	}
+}
+
+func (p *pinAdapter) setSoftPWMLevel(l gpio.Level) {
+	if err := p.Out(l); err != nil {
+		p.logger.Error("software pwm: failed to set pin level", "level", l, "err", err)
+	}
+}
+
+// waitUntilOrStop is sleepUntil with an early-exit channel, so stopping
+// software PWM never waits longer than the current half-period.
+func waitUntilOrStop(deadline time.Time, stop <-chan struct{}) bool {
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		select {
+		case <-stop:
+			return false
+		default:
+			return true
+		}
+	}
+
+	if remaining > busySpinThreshold {
+		timer := time.NewTimer(remaining - busySpinThreshold)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-stop:
+			return false
+		}
+	}
+
+	for time.Now().Before(deadline) {
+		select {
+		case <-stop:
+			return false
+		default:
+		}
+	}
+	return true
+}
+
+// stopSoftPWM halts and waits for any running software PWM goroutine. It is
+// a no-op if none is running.
+func (p *pinAdapter) stopSoftPWM() {
+	if loop := p.softPWM.Swap(nil); loop != nil {
+		close(loop.stop)
+		<-loop.done
+	}
+}
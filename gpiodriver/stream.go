@@ -0,0 +1,263 @@
+package gpiodriver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/warthog618/go-gpiocdev"
+	"golang.org/x/sys/unix"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiostream"
+)
+
+var (
+	_ gpiostream.PinIn  = (*pinAdapter)(nil)
+	_ gpiostream.PinOut = (*pinAdapter)(nil)
+)
+
+// streamEventBuffer is the size of the channel used to ferry edge events from
+// handleEvent to a StreamIn capture in progress.
+const streamEventBuffer = 64
+
+// StreamIn reads the pin at the specified resolution to fill the provided
+// buffer.
+//
+// Only *gpiostream.BitStream and *gpiostream.EdgeStream are supported.
+// *gpiostream.EdgeStream is the native representation: since the underlying
+// line only ever delivers edge events, captures are reconstructed from
+// kernel-timestamped transitions rather than true periodic sampling, so an
+// EdgeStream capture has far less jitter than a BitStream one, which polls
+// Read() at Freq instead.
+func (p *pinAdapter) StreamIn(pull gpio.Pull, s gpiostream.Stream) error {
+	switch b := s.(type) {
+	case *gpiostream.EdgeStream:
+		return p.streamInEdges(pull, b)
+	case *gpiostream.BitStream:
+		return p.streamInBits(pull, b)
+	default:
+		return fmt.Errorf("gpiodriver: unsupported stream type %T", s)
+	}
+}
+
+func (p *pinAdapter) streamInEdges(pull gpio.Pull, b *gpiostream.EdgeStream) error {
+	if len(b.Edges) == 0 {
+		return nil
+	}
+	if b.Freq == 0 {
+		return fmt.Errorf("gpiodriver: EdgeStream.Freq must be set")
+	}
+
+	if err := p.In(pull, gpio.BothEdges); err != nil {
+		return fmt.Errorf("gpiodriver: failed to configure pin for streaming: %w", err)
+	}
+
+	// Seed last from the moment the line was armed for edge detection, in
+	// the same CLOCK_MONOTONIC epoch as LineEvent.Timestamp, so the first
+	// edge's segment records how long the starting level was actually held
+	// instead of a silent 0. If the clock can't be read, fall back to the
+	// old (lossy) behavior for just that first segment.
+	last, haveLast := monotonicNow()
+
+	sink := make(chan gpiocdev.LineEvent, streamEventBuffer)
+	p.streamSink.Store(&sink)
+	defer p.streamSink.Store(nil)
+
+	period := b.Freq.Period()
+
+	// The stream is defined to start High; record a leading zero-duration
+	// edge if we actually start Low.
+	i := 0
+	if p.Read() == gpio.Low {
+		b.Edges[0] = 0
+		i = 1
+	}
+
+	var (
+		lastSeq uint32
+		haveSeq bool
+	)
+
+	for ; i < len(b.Edges); i++ {
+		event, ok := <-sink
+		if !ok {
+			break
+		}
+
+		if haveSeq && event.Seqno != lastSeq+1 {
+			p.logger.Warn(
+				"dropped edge events during stream capture",
+				"dropped", event.Seqno-lastSeq-1)
+		}
+
+		var ticks time.Duration
+		if haveLast {
+			ticks = (event.Timestamp - last) / period
+		}
+		last, lastSeq, haveLast, haveSeq = event.Timestamp, event.Seqno, true, true
+
+		if ticks > 0xffff {
+			ticks = 0xffff
+		}
+		b.Edges[i] = uint16(ticks)
+	}
+
+	return nil
+}
+
+// monotonicNow reads the current CLOCK_MONOTONIC time, the same clock
+// LineEvent.Timestamp is reported against on Linux v5.7+ (see EdgeEvent's
+// doc comment), so it can seed a "time since capture started" baseline
+// before any edge has actually been observed. ok is false if the clock
+// couldn't be read, in which case the caller should treat it as unseeded.
+func monotonicNow() (now time.Duration, ok bool) {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &ts); err != nil {
+		return 0, false
+	}
+	return time.Duration(ts.Nano()), true
+}
+
+func (p *pinAdapter) streamInBits(pull gpio.Pull, b *gpiostream.BitStream) error {
+	if len(b.Bits) == 0 {
+		return nil
+	}
+	if b.Freq == 0 {
+		return fmt.Errorf("gpiodriver: BitStream.Freq must be set")
+	}
+
+	if err := p.In(pull, gpio.NoEdge); err != nil {
+		return fmt.Errorf("gpiodriver: failed to configure pin for streaming: %w", err)
+	}
+
+	period := b.Freq.Period()
+	deadline := time.Now()
+
+	for bitIndex := 0; bitIndex < len(b.Bits)*8; bitIndex++ {
+		sleepUntil(deadline)
+		deadline = deadline.Add(period)
+
+		var bit byte
+		if p.Read() == gpio.High {
+			bit = 1
+		}
+
+		byteIndex := bitIndex / 8
+		shift := bitIndex % 8
+		if b.LSBF {
+			b.Bits[byteIndex] |= bit << shift
+		} else {
+			b.Bits[byteIndex] |= bit << (7 - shift)
+		}
+	}
+
+	return nil
+}
+
+// StreamOut streams s to the pin.
+//
+// Only *gpiostream.BitStream and *gpiostream.EdgeStream are supported.
+func (p *pinAdapter) StreamOut(s gpiostream.Stream) error {
+	switch b := s.(type) {
+	case *gpiostream.BitStream:
+		return p.streamOutBits(b)
+	case *gpiostream.EdgeStream:
+		return p.streamOutEdges(b)
+	default:
+		return fmt.Errorf("gpiodriver: unsupported stream type %T", s)
+	}
+}
+
+func (p *pinAdapter) streamOutBits(b *gpiostream.BitStream) error {
+	if b.Freq == 0 {
+		return fmt.Errorf("gpiodriver: BitStream.Freq must be set")
+	}
+
+	initialized, err := p.initPin(gpiocdev.AsOutput(0))
+	if err != nil {
+		return fmt.Errorf("gpiodriver: failed to initialize pin for streaming: %w", err)
+	}
+	line := p.line.Load()
+	if !initialized {
+		if err := line.Reconfigure(gpiocdev.AsOutput(0)); err != nil {
+			return fmt.Errorf("gpiodriver: failed to reconfigure pin as output: %w", err)
+		}
+	}
+
+	period := b.Freq.Period()
+	deadline := time.Now()
+
+	for bitIndex := 0; bitIndex < len(b.Bits)*8; bitIndex++ {
+		byteIndex := bitIndex / 8
+		shift := bitIndex % 8
+
+		var bit byte
+		if b.LSBF {
+			bit = (b.Bits[byteIndex] >> shift) & 1
+		} else {
+			bit = (b.Bits[byteIndex] >> (7 - shift)) & 1
+		}
+
+		sleepUntil(deadline)
+		deadline = deadline.Add(period)
+
+		if err := line.SetValue(int(bit)); err != nil {
+			return fmt.Errorf("gpiodriver: failed to set pin value: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *pinAdapter) streamOutEdges(b *gpiostream.EdgeStream) error {
+	if b.Freq == 0 {
+		return fmt.Errorf("gpiodriver: EdgeStream.Freq must be set")
+	}
+
+	level := 1 // EdgeStream is defined to start High.
+	initialized, err := p.initPin(gpiocdev.AsOutput(level))
+	if err != nil {
+		return fmt.Errorf("gpiodriver: failed to initialize pin for streaming: %w", err)
+	}
+	line := p.line.Load()
+	if !initialized {
+		if err := line.Reconfigure(gpiocdev.AsOutput(level)); err != nil {
+			return fmt.Errorf("gpiodriver: failed to reconfigure pin as output: %w", err)
+		}
+	}
+
+	period := b.Freq.Period()
+	deadline := time.Now()
+
+	for i, ticks := range b.Edges {
+		deadline = deadline.Add(period * time.Duration(ticks))
+		if i == 0 && ticks == 0 {
+			// A zero-duration first edge only signals a Low start; it
+			// doesn't itself toggle the level.
+			level = 0
+		} else {
+			level ^= 1
+		}
+
+		sleepUntil(deadline)
+		if err := line.SetValue(level); err != nil {
+			return fmt.Errorf("gpiodriver: failed to set pin value: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// sleepUntil blocks until deadline, busy spinning for the final stretch to
+// avoid the OS timer's imprecision at short durations, the same thresholding
+// used by WaitForEdge.
+func sleepUntil(deadline time.Time) {
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return
+	}
+	if remaining > busySpinThreshold {
+		time.Sleep(remaining - busySpinThreshold)
+	}
+	for time.Now().Before(deadline) {
+	}
+}
@@ -0,0 +1,149 @@
+package gpiodriver
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiostream"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/pin"
+)
+
+// TraceLevel selects which GPIO operations a tracePin logs, set via the
+// GPIOC_DEBUG environment variable (or Option WithDebug).
+type TraceLevel string
+
+const (
+	// TraceOff disables tracing.
+	TraceOff TraceLevel = ""
+	// TraceAPI logs In/Read/Out/PWM calls.
+	TraceAPI TraceLevel = "api"
+	// TraceEvents logs edge detections reported through WaitForEdge.
+	TraceEvents TraceLevel = "events"
+	// TraceAll logs both.
+	TraceAll TraceLevel = "all"
+)
+
+// ParseTraceLevel parses a --debug flag value: "", "api", "events", or
+// "all".
+func ParseTraceLevel(s string) (TraceLevel, error) {
+	switch l := TraceLevel(strings.ToLower(s)); l {
+	case TraceOff, TraceAPI, TraceEvents, TraceAll:
+		return l, nil
+	default:
+		return "", fmt.Errorf("gpiodriver: unknown trace level %q", s)
+	}
+}
+
+// traceLevelFromEnv parses $GPIOC_DEBUG, defaulting to TraceOff for an
+// empty or unrecognized value.
+func traceLevelFromEnv() TraceLevel {
+	level, err := ParseTraceLevel(os.Getenv("GPIOC_DEBUG"))
+	if err != nil {
+		return TraceOff
+	}
+	return level
+}
+
+// tracePin embeds a *pinAdapter and logs every observable GPIO transaction
+// at debug level: line configuration and reads/writes (TraceAPI), and edge
+// detections (TraceEvents), each with elapsed-since-last-event timing.
+//
+// Embedding, rather than forwarding one-off methods, means tracePin also
+// promotes pin.PinFunc (Func/SupportedFuncs/SetFunc), EdgePin
+// (InWithOptions/NextEdge/DroppedEvents), gpiostream.PinIn/PinOut
+// (StreamIn/StreamOut), and unwrapPinAdapter from *pinAdapter, so
+// registering a tracePin in place of its *pinAdapter doesn't hide those
+// interfaces from consumers like NewLineGroup while tracing is enabled.
+type tracePin struct {
+	*pinAdapter
+	logger *slog.Logger
+	level  TraceLevel
+
+	mu       sync.Mutex
+	lastTime time.Time
+}
+
+var (
+	_ gpio.PinIO        = (*tracePin)(nil)
+	_ pin.PinFunc       = (*tracePin)(nil)
+	_ EdgePin           = (*tracePin)(nil)
+	_ gpiostream.PinIn  = (*tracePin)(nil)
+	_ gpiostream.PinOut = (*tracePin)(nil)
+)
+
+func newTracePin(adapter *pinAdapter, level TraceLevel) *tracePin {
+	return &tracePin{
+		pinAdapter: adapter,
+		logger:     adapter.logger.With("trace", true),
+		level:      level,
+	}
+}
+
+func (t *tracePin) In(pull gpio.Pull, edge gpio.Edge) error {
+	err := t.pinAdapter.In(pull, edge)
+	t.trace(TraceAPI, "in", "err", err)
+	return err
+}
+
+func (t *tracePin) Read() gpio.Level {
+	l := t.pinAdapter.Read()
+	t.trace(TraceAPI, "read", "direction", "←", "level", l)
+	return l
+}
+
+func (t *tracePin) WaitForEdge(timeout time.Duration) bool {
+	fired := t.pinAdapter.WaitForEdge(timeout)
+	if fired {
+		t.trace(TraceEvents, "edge", "level", t.pinAdapter.Read())
+	}
+	return fired
+}
+
+func (t *tracePin) Out(l gpio.Level) error {
+	err := t.pinAdapter.Out(l)
+	t.trace(TraceAPI, "out", "direction", "→", "level", l, "err", err)
+	return err
+}
+
+func (t *tracePin) PWM(duty gpio.Duty, f physic.Frequency) error {
+	err := t.pinAdapter.PWM(duty, f)
+	t.trace(TraceAPI, "pwm", "duty", duty, "freq", f, "err", err)
+	return err
+}
+
+// trace logs op with kv if at is enabled by t.level, prefixing it with the
+// pin's chip, line, direction, pull, edge, and the time elapsed since the
+// last traced event on this pin.
+func (t *tracePin) trace(at TraceLevel, op string, kv ...any) {
+	if t.level != at && t.level != TraceAll {
+		return
+	}
+
+	now := time.Now()
+	t.mu.Lock()
+	var elapsed time.Duration
+	if !t.lastTime.IsZero() {
+		elapsed = now.Sub(t.lastTime)
+	}
+	t.lastTime = now
+	t.mu.Unlock()
+
+	info := t.pinAdapter.info.Load()
+	args := make([]any, 0, 10+len(kv))
+	args = append(args,
+		"chip", t.pinAdapter.chip.Name,
+		"line", info.Offset,
+		"direction", info.Config.Direction,
+		"pull", t.pinAdapter.Pull(),
+		"edge", info.Config.EdgeDetection,
+		"elapsed", elapsed)
+	args = append(args, kv...)
+
+	t.logger.Debug("gpio "+op, args...)
+}
@@ -0,0 +1,169 @@
+package xcli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// ColorOptions customizes the ANSI SGR codes (e.g. "32" for green, "1;31"
+// for bold red) NewColoredLogger/NewColoredLoggerFor use to highlight each
+// level, attribute keys, and the message, mirroring Elara6331/logger's
+// CLILogger color fields. An empty code leaves the corresponding text
+// unstyled.
+type ColorOptions struct {
+	Debug   string
+	Info    string
+	Warn    string
+	Error   string
+	Key     string
+	Message string
+}
+
+// DefaultColorOptions returns the colors NewColoredLogger uses when no
+// ColorOptions is given.
+func DefaultColorOptions() ColorOptions {
+	return ColorOptions{
+		Debug: "2",    // faint
+		Info:  "36",   // cyan
+		Warn:  "33",   // yellow
+		Error: "91;1", // bold bright red
+		Key:   "2",    // faint
+	}
+}
+
+func (c ColorOptions) levelCode(l slog.Level) string {
+	switch {
+	case l < slog.LevelInfo:
+		return c.Debug
+	case l < slog.LevelWarn:
+		return c.Info
+	case l < slog.LevelError:
+		return c.Warn
+	default:
+		return c.Error
+	}
+}
+
+// resolveColorEnv decides whether colored output should be used for a
+// writer that is (or isn't) a terminal, honoring the conventional
+// NO_COLOR / FORCE_COLOR / CLICOLOR / CLICOLOR_FORCE environment matrix:
+// NO_COLOR always disables colors; FORCE_COLOR or CLICOLOR_FORCE enables
+// them even for a non-terminal; CLICOLOR=0 disables them; otherwise colors
+// follow isTTY.
+func resolveColorEnv(isTTY bool) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	if _, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		return true
+	}
+	if _, ok := os.LookupEnv("CLICOLOR_FORCE"); ok {
+		return true
+	}
+	if v, ok := os.LookupEnv("CLICOLOR"); ok && v == "0" {
+		return false
+	}
+	return isTTY
+}
+
+// coloredHandler renders records as "time LEVEL message key=val", tinting
+// the level, message, and attribute keys per its ColorOptions.
+type coloredHandler struct {
+	out      io.Writer
+	minLevel slog.Leveler
+	colors   ColorOptions
+	attrs    []slog.Attr
+	groupPfx string
+	mu       *sync.Mutex
+}
+
+func newColoredHandler(out io.Writer, opts *slog.HandlerOptions, colors ColorOptions) *coloredHandler {
+	var minLevel slog.Leveler = slog.LevelInfo
+	if opts != nil && opts.Level != nil {
+		minLevel = opts.Level
+	}
+	return &coloredHandler{out: out, minLevel: minLevel, colors: colors, mu: new(sync.Mutex)}
+}
+
+func (h *coloredHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.minLevel.Level()
+}
+
+func (h *coloredHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	buf.WriteString(r.Time.Format("15:04:05.000"))
+	buf.WriteByte(' ')
+	writeSGR(&buf, h.colors.levelCode(r.Level), levelLabel(r.Level))
+	buf.WriteByte(' ')
+	writeSGR(&buf, h.colors.Message, r.Message)
+
+	for _, a := range h.attrs {
+		writeColoredAttr(&buf, h.groupPfx, a, h.colors.Key)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		writeColoredAttr(&buf, h.groupPfx, a, h.colors.Key)
+		return true
+	})
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.out.Write(buf.Bytes())
+	return err
+}
+
+func (h *coloredHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	cp := *h
+	cp.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &cp
+}
+
+func (h *coloredHandler) WithGroup(name string) slog.Handler {
+	cp := *h
+	if cp.groupPfx == "" {
+		cp.groupPfx = name
+	} else {
+		cp.groupPfx = cp.groupPfx + "." + name
+	}
+	return &cp
+}
+
+func writeSGR(buf *bytes.Buffer, code, s string) {
+	if code == "" {
+		buf.WriteString(s)
+		return
+	}
+	fmt.Fprintf(buf, "\x1b[%sm%s\x1b[0m", code, s)
+}
+
+func writeColoredAttr(buf *bytes.Buffer, groupPfx string, a slog.Attr, keyColor string) {
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	buf.WriteByte(' ')
+	key := a.Key
+	if groupPfx != "" {
+		key = groupPfx + "." + key
+	}
+	writeSGR(buf, keyColor, key)
+	buf.WriteByte('=')
+	fmt.Fprintf(buf, "%v", a.Value.Any())
+}
+
+func levelLabel(l slog.Level) string {
+	switch {
+	case l < slog.LevelInfo:
+		return "DEBUG"
+	case l < slog.LevelWarn:
+		return "INFO"
+	case l < slog.LevelError:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
@@ -0,0 +1,99 @@
+package xcli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// compactHandler renders records as single-letter level prefixed lines, e.g.
+// "I[2024-04-06T19:04:20.584-07:00] msg key=val", the format used by the
+// go-log project's compact encoder.
+type compactHandler struct {
+	out      io.Writer
+	minLevel slog.Leveler
+	attrs    []slog.Attr
+	groupPfx string
+	mu       *sync.Mutex
+}
+
+func newCompactHandler(out io.Writer, opts *slog.HandlerOptions) *compactHandler {
+	var minLevel slog.Leveler = slog.LevelInfo
+	if opts != nil && opts.Level != nil {
+		minLevel = opts.Level
+	}
+	return &compactHandler{out: out, minLevel: minLevel, mu: new(sync.Mutex)}
+}
+
+func (h *compactHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.minLevel.Level()
+}
+
+func (h *compactHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	buf.WriteByte(compactLevelByte(r.Level))
+	buf.WriteByte('[')
+	buf.WriteString(r.Time.Format("2006-01-02T15:04:05.000Z07:00"))
+	buf.WriteString("] ")
+	buf.WriteString(r.Message)
+
+	for _, a := range h.attrs {
+		writeCompactAttr(&buf, h.groupPfx, a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		writeCompactAttr(&buf, h.groupPfx, a)
+		return true
+	})
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.out.Write(buf.Bytes())
+	return err
+}
+
+func (h *compactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	cp := *h
+	cp.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &cp
+}
+
+func (h *compactHandler) WithGroup(name string) slog.Handler {
+	cp := *h
+	if cp.groupPfx == "" {
+		cp.groupPfx = name
+	} else {
+		cp.groupPfx = cp.groupPfx + "." + name
+	}
+	return &cp
+}
+
+func writeCompactAttr(buf *bytes.Buffer, groupPfx string, a slog.Attr) {
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	buf.WriteByte(' ')
+	if groupPfx != "" {
+		buf.WriteString(groupPfx)
+		buf.WriteByte('.')
+	}
+	buf.WriteString(a.Key)
+	buf.WriteByte('=')
+	fmt.Fprintf(buf, "%v", a.Value.Any())
+}
+
+func compactLevelByte(l slog.Level) byte {
+	switch {
+	case l < slog.LevelInfo:
+		return 'D'
+	case l < slog.LevelWarn:
+		return 'I'
+	case l < slog.LevelError:
+		return 'W'
+	default:
+		return 'E'
+	}
+}
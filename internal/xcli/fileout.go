@@ -0,0 +1,70 @@
+package xcli
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// newFileHandler builds the rotating JSON file sink described by cfg.File.
+func newFileHandler(cfg LogConfig) slog.Handler {
+	w := &lumberjack.Logger{
+		Filename:   cfg.File.Path,
+		MaxSize:    cfg.File.MaxSize,
+		MaxAge:     cfg.File.MaxAge,
+		MaxBackups: cfg.File.MaxBackups,
+		Compress:   cfg.File.Compress,
+	}
+	return slog.NewJSONHandler(w, &slog.HandlerOptions{Level: cfg.Level})
+}
+
+// fanoutHandler forwards every record to each of its handlers, e.g. a
+// colored stream for the terminal and a plain JSON file, each with their own
+// formatting and level filtering.
+type fanoutHandler struct {
+	handlers []slog.Handler
+}
+
+func newFanoutHandler(handlers ...slog.Handler) slog.Handler {
+	return &fanoutHandler{handlers: handlers}
+}
+
+func (f *fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f.handlers {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := make([]slog.Handler, len(f.handlers))
+	for i, h := range f.handlers {
+		next[i] = h.WithAttrs(attrs)
+	}
+	return &fanoutHandler{handlers: next}
+}
+
+func (f *fanoutHandler) WithGroup(name string) slog.Handler {
+	next := make([]slog.Handler, len(f.handlers))
+	for i, h := range f.handlers {
+		next[i] = h.WithGroup(name)
+	}
+	return &fanoutHandler{handlers: next}
+}
@@ -0,0 +1,109 @@
+package xcli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// journaldStream reports whether w is the fd systemd connected to this
+// process' standard streams, per the $JOURNAL_STREAM protocol described in
+// systemd.exec(5): the env var holds "device:inode", which is compared
+// against an fstat of w (like Forgejo's color_console_other.go logic).
+func journaldStream(w WritableFd) bool {
+	devIno := os.Getenv("JOURNAL_STREAM")
+	if devIno == "" {
+		return false
+	}
+
+	dev, ino, ok := strings.Cut(devIno, ":")
+	if !ok {
+		return false
+	}
+	wantDev, err := strconv.ParseUint(dev, 10, 64)
+	if err != nil {
+		return false
+	}
+	wantIno, err := strconv.ParseUint(ino, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	var stat syscall.Stat_t
+	if err := syscall.Fstat(int(w.Fd()), &stat); err != nil {
+		return false
+	}
+
+	return uint64(stat.Dev) == wantDev && uint64(stat.Ino) == wantIno
+}
+
+// journaldHandler wraps a plain slog.TextHandler and prefixes each record
+// with its syslog priority as "<N>", the format journald expects from
+// processes logging to a stream it didn't allocate itself (see
+// systemd.exec(5), "Logging and Standard Input/Output").
+type journaldHandler struct {
+	out   io.Writer
+	inner slog.Handler
+	buf   *bytes.Buffer
+	mu    *sync.Mutex
+}
+
+func newJournaldHandler(out io.Writer, opts *slog.HandlerOptions) *journaldHandler {
+	buf := new(bytes.Buffer)
+	return &journaldHandler{
+		out:   out,
+		inner: slog.NewTextHandler(buf, opts),
+		buf:   buf,
+		mu:    new(sync.Mutex),
+	}
+}
+
+func (h *journaldHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *journaldHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.buf.Reset()
+	if err := h.inner.Handle(ctx, r); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(h.out, "<%d>%s", syslogPriority(r.Level), h.buf.String())
+	return err
+}
+
+func (h *journaldHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &journaldHandler{out: h.out, inner: h.inner.WithAttrs(attrs), buf: h.buf, mu: h.mu}
+}
+
+func (h *journaldHandler) WithGroup(name string) slog.Handler {
+	return &journaldHandler{out: h.out, inner: h.inner.WithGroup(name), buf: h.buf, mu: h.mu}
+}
+
+// syslogPriority maps a slog.Level to the syslog(3) severity journald reads
+// out of a "<N>" line prefix. There's no standard mapping past Error, so
+// anything more severe is clamped to crit.
+func syslogPriority(l slog.Level) int {
+	switch {
+	case l < slog.LevelInfo:
+		return 7 // debug
+	case l < slog.LevelWarn:
+		return 6 // info
+	case l < slog.LevelError:
+		return 4 // warning
+	case l == slog.LevelError:
+		return 3 // err
+	default:
+		return 2 // crit
+	}
+}
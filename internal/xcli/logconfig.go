@@ -0,0 +1,164 @@
+package xcli
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/lmittmann/tint"
+	"github.com/mattn/go-isatty"
+)
+
+// LogFormat selects how log records are rendered by NewLogger.
+type LogFormat string
+
+const (
+	// LogFormatAuto picks LogFormatText for a terminal and LogFormatLogfmt
+	// otherwise.
+	LogFormatAuto    LogFormat = "auto"
+	LogFormatText    LogFormat = "text"
+	LogFormatLogfmt  LogFormat = "logfmt"
+	LogFormatJSON    LogFormat = "json"
+	LogFormatCompact LogFormat = "compact"
+)
+
+// ParseLogFormat parses a --log.format flag value.
+func ParseLogFormat(s string) (LogFormat, error) {
+	switch f := LogFormat(s); f {
+	case LogFormatAuto, LogFormatText, LogFormatLogfmt, LogFormatJSON, LogFormatCompact:
+		return f, nil
+	default:
+		return "", fmt.Errorf("xcli: unknown log format %q", s)
+	}
+}
+
+// ParseLogLevel parses a --log.level flag value.
+func ParseLogLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("xcli: unknown log level %q", s)
+	}
+}
+
+// ColorMode is a tri-state override for whether NewLogger's text/auto
+// formats colorize their output, mirroring Prometheus' promlog flags and
+// Gin's ForceConsoleColor/DisableConsoleColor.
+type ColorMode string
+
+const (
+	// ColorAuto decides based on $NO_COLOR, $FORCE_COLOR, $CLICOLOR_FORCE,
+	// $CLICOLOR and whether Writer is a terminal.
+	ColorAuto   ColorMode = "auto"
+	ColorAlways ColorMode = "always"
+	ColorNever  ColorMode = "never"
+)
+
+// ParseColorMode parses a --log.color flag value.
+func ParseColorMode(s string) (ColorMode, error) {
+	switch c := ColorMode(s); c {
+	case ColorAuto, ColorAlways, ColorNever:
+		return c, nil
+	default:
+		return "", fmt.Errorf("xcli: unknown log color mode %q", s)
+	}
+}
+
+// LogFileConfig enables and tunes the optional rotating file sink added to
+// NewLogger's output by setting LogConfig.File.
+type LogFileConfig struct {
+	// Path enables file logging when non-empty.
+	Path string
+	// MaxSize is the maximum size in megabytes of the log file before it
+	// gets rotated. Defaults to lumberjack's own default (100) if zero.
+	MaxSize int
+	// MaxAge is the maximum number of days to retain old log files, based
+	// on the timestamp encoded in their filename. Zero disables age-based
+	// cleanup.
+	MaxAge int
+	// MaxBackups is the maximum number of old log files to retain. Zero
+	// retains all of them.
+	MaxBackups int
+	// Compress gzips rotated log files.
+	Compress bool
+}
+
+// LogConfig configures NewLogger. It's meant to be populated directly from
+// the --log.format/--log.level/--log.color/--log.file* flags.
+type LogConfig struct {
+	// Writer is where log records are written. It must implement Fd() so
+	// LogFormatAuto and ColorAuto can inspect the underlying file; wrap a
+	// plain io.Writer with NewColoredLoggerFor instead if it doesn't.
+	Writer WritableFd
+	Format LogFormat
+	Level  slog.Level
+	Color  ColorMode
+	// File, if its Path is set, additionally writes JSON-formatted records
+	// to a rotating log file, independent of Format/Color.
+	File LogFileConfig
+}
+
+// NewLogger builds a logger from cfg. A JOURNAL_STREAM match on cfg.Writer
+// takes priority over cfg.Format/cfg.Color entirely, the same as
+// NewColoredLogger. If cfg.File.Path is set, records are additionally
+// written there as JSON, regardless of cfg.Format.
+func NewLogger(cfg LogConfig) *slog.Logger {
+	handler := newStreamHandler(cfg)
+	if cfg.File.Path != "" {
+		handler = newFanoutHandler(handler, newFileHandler(cfg))
+	}
+	return slog.New(handler)
+}
+
+func newStreamHandler(cfg LogConfig) slog.Handler {
+	opts := &slog.HandlerOptions{Level: cfg.Level}
+
+	if journaldStream(cfg.Writer) {
+		return newJournaldHandler(cfg.Writer, opts)
+	}
+
+	format := cfg.Format
+	if format == "" || format == LogFormatAuto {
+		if isatty.IsTerminal(cfg.Writer.Fd()) {
+			format = LogFormatText
+		} else {
+			format = LogFormatLogfmt
+		}
+	}
+
+	switch format {
+	case LogFormatJSON:
+		return slog.NewJSONHandler(cfg.Writer, opts)
+	case LogFormatLogfmt:
+		return slog.NewTextHandler(cfg.Writer, opts)
+	case LogFormatCompact:
+		return newCompactHandler(cfg.Writer, opts)
+	default: // LogFormatText
+		color := resolveColor(cfg.Color, cfg.Writer)
+		return newTraceColorHandler(tint.NewHandler(cfg.Writer, &tint.Options{
+			Level:   cfg.Level,
+			NoColor: !color,
+		}), color)
+	}
+}
+
+// resolveColor applies mode against the NO_COLOR/FORCE_COLOR/CLICOLOR(_FORCE)
+// environment matrix and isatty (see resolveColorEnv); ColorAlways/ColorNever
+// force the outcome regardless of either.
+func resolveColor(mode ColorMode, o WritableFd) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	default:
+		return resolveColorEnv(isatty.IsTerminal(o.Fd()))
+	}
+}
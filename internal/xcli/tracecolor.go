@@ -0,0 +1,92 @@
+package xcli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+const (
+	traceColorReset   = "\x1b[0m"
+	traceColorOut     = "\x1b[32m"   // → (output) in green
+	traceColorIn      = "\x1b[36m"   // ← (input) in cyan
+	traceColorRising  = "\x1b[1;32m" // rising edge in bold green
+	traceColorFalling = "\x1b[1;31m" // falling edge in bold red
+)
+
+// newTraceColorHandler wraps inner, highlighting the records written by
+// gpiodriver's GPIO trace logging subsystem (attr "trace"=true, see
+// gpiodriver.WithDebug): it colorizes the "direction" attr's →/← arrows and
+// highlights "edge"-message records rising (level=true) or falling
+// (level=false). Non-trace records, and trace records when color is
+// disabled, pass through unmodified.
+func newTraceColorHandler(inner slog.Handler, color bool) slog.Handler {
+	if !color {
+		return inner
+	}
+	return &traceColorHandler{inner: inner}
+}
+
+type traceColorHandler struct {
+	inner slog.Handler
+}
+
+func (h *traceColorHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *traceColorHandler) Handle(ctx context.Context, r slog.Record) error {
+	isTrace := false
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == "trace" && a.Value.Kind() == slog.KindBool && a.Value.Bool() {
+			isTrace = true
+		}
+		return true
+	})
+	if !isTrace {
+		return h.inner.Handle(ctx, r)
+	}
+
+	colored := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		switch {
+		case a.Key == "direction" && a.Value.Kind() == slog.KindString:
+			colored.AddAttrs(slog.String(a.Key, colorDirection(a.Value.String())))
+		case a.Key == "level" && r.Message == "gpio edge":
+			colored.AddAttrs(slog.String(a.Key, colorEdge(a.Value)))
+		default:
+			colored.AddAttrs(a)
+		}
+		return true
+	})
+	return h.inner.Handle(ctx, colored)
+}
+
+func (h *traceColorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &traceColorHandler{inner: h.inner.WithAttrs(attrs)}
+}
+
+func (h *traceColorHandler) WithGroup(name string) slog.Handler {
+	return &traceColorHandler{inner: h.inner.WithGroup(name)}
+}
+
+func colorDirection(arrow string) string {
+	switch arrow {
+	case "→":
+		return traceColorOut + arrow + traceColorReset
+	case "←":
+		return traceColorIn + arrow + traceColorReset
+	default:
+		return arrow
+	}
+}
+
+// colorEdge renders level (a gpio.Level, logged as a fmt.Stringer yielding
+// "High" or "Low") in bold green for a rising edge and bold red for falling.
+func colorEdge(level slog.Value) string {
+	s := fmt.Sprintf("%v", level.Any())
+	if s == "High" {
+		return traceColorRising + s + traceColorReset
+	}
+	return traceColorFalling + s + traceColorReset
+}
@@ -3,9 +3,7 @@ package xcli
 import (
 	"io"
 	"log/slog"
-	"os"
 
-	"github.com/lmittmann/tint"
 	"github.com/mattn/go-isatty"
 )
 
@@ -14,13 +12,43 @@ type WritableFd interface {
 	Fd() uintptr
 }
 
-// NewColoredLogger sets the global slog.Logger to use colored logging.
-// If $NO_COLOR is set, it will disable colored logging.
-func NewColoredLogger(o WritableFd, l slog.Leveler) *slog.Logger {
-	handler := tint.NewHandler(o, &tint.Options{
-		Level:   l,
-		NoColor: os.Getenv("NO_COLOR") != "" && isatty.IsTerminal(o.Fd()),
-	})
-	logger := slog.New(handler)
-	return logger
+// NewColoredLogger returns a slog.Logger that uses colored logging,
+// customized by an optional ColorOptions (DefaultColorOptions is used if
+// colors is omitted).
+//
+// Colors follow the conventional NO_COLOR/FORCE_COLOR/CLICOLOR/CLICOLOR_FORCE
+// environment matrix: $NO_COLOR always disables colors, $FORCE_COLOR and
+// $CLICOLOR_FORCE enable them even when o isn't a terminal, $CLICOLOR=0
+// disables them, and otherwise colors follow whether o is a terminal.
+//
+// If o is the fd systemd connected to this process' stdio (detected via
+// $JOURNAL_STREAM), colored output is skipped in favor of a handler that
+// prefixes each record with its syslog priority, so journald assigns it the
+// right level instead of defaulting to info.
+func NewColoredLogger(o WritableFd, l slog.Leveler, colors ...ColorOptions) *slog.Logger {
+	if journaldStream(o) {
+		return slog.New(newJournaldHandler(o, &slog.HandlerOptions{Level: l}))
+	}
+	return newColoredLoggerFor(o, isatty.IsTerminal(o.Fd()), l, colors...)
+}
+
+// NewColoredLoggerFor is like NewColoredLogger, but accepts any io.Writer.
+// Writers that don't implement Fd() (e.g. a bytes.Buffer in a test) are
+// treated as non-terminals and never get journald or isatty detection.
+func NewColoredLoggerFor(w io.Writer, l slog.Leveler, colors ...ColorOptions) *slog.Logger {
+	if o, ok := w.(WritableFd); ok {
+		return NewColoredLogger(o, l, colors...)
+	}
+	return newColoredLoggerFor(w, false, l, colors...)
+}
+
+func newColoredLoggerFor(w io.Writer, isTTY bool, l slog.Leveler, colors ...ColorOptions) *slog.Logger {
+	opts := DefaultColorOptions()
+	if len(colors) > 0 {
+		opts = colors[0]
+	}
+	if !resolveColorEnv(isTTY) {
+		opts = ColorOptions{}
+	}
+	return slog.New(newColoredHandler(w, &slog.HandlerOptions{Level: l}, opts))
 }